@@ -0,0 +1,217 @@
+package pg
+
+import (
+	"bufio"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+)
+
+// Backend/frontend message type bytes, see
+// http://www.postgresql.org/docs/current/static/protocol-message-formats.html
+const (
+	msgAuthentication  = 'R'
+	msgBackendKeyData  = 'K'
+	msgBind            = 'B'
+	msgClose           = 'C'
+	msgCommandComplete = 'C'
+	msgCopyData        = 'd'
+	msgCopyDone        = 'c'
+	msgCopyFail        = 'f'
+	msgCopyInResponse  = 'G'
+	msgCopyOutResponse = 'H'
+	msgDataRow         = 'D'
+	msgDescribe        = 'D'
+	msgEmptyQueryResp  = 'I'
+	msgErrorResponse   = 'E'
+	msgExecute         = 'E'
+	msgNoData          = 'n'
+	msgNotification    = 'A'
+	msgParameterStatus = 'S'
+	msgParse           = 'P'
+	msgParseComplete   = '1'
+	msgBindComplete    = '2'
+	msgQuery           = 'Q'
+	msgReadyForQuery   = 'Z'
+	msgRowDescription  = 'T'
+	msgSync            = 'S'
+	msgTerminate       = 'X'
+)
+
+// conn is a single connection to the PostgreSQL backend plus the
+// buffered readers/writers used to frame protocol messages.
+type conn struct {
+	netConn net.Conn
+	rd      *bufio.Reader
+	wr      *bufio.Writer
+
+	processID int32
+	secretKey int32
+}
+
+func dialConn(opt *Options) (*conn, error) {
+	netConn, err := net.Dial(opt.getNetwork(), opt.getAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	cn := &conn{
+		netConn: netConn,
+		rd:      bufio.NewReader(netConn),
+		wr:      bufio.NewWriter(netConn),
+	}
+
+	if err := cn.startup(opt); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	return cn, nil
+}
+
+func (cn *conn) Close() error {
+	_ = cn.writeTerminate()
+	return cn.netConn.Close()
+}
+
+//
+// message framing
+//
+
+func (cn *conn) writeInt32(n int32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(n))
+	cn.wr.Write(b[:])
+}
+
+func (cn *conn) writeInt16(n int16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], uint16(n))
+	cn.wr.Write(b[:])
+}
+
+func (cn *conn) writeString(s string) {
+	cn.wr.WriteString(s)
+	cn.wr.WriteByte(0)
+}
+
+// writeMessage writes a single frontend message: a type byte followed
+// by a big-endian length (including the 4 length bytes, excluding the
+// type byte) and the body.
+func (cn *conn) writeMessage(typ byte, body []byte) error {
+	if typ != 0 {
+		cn.wr.WriteByte(typ)
+	}
+	cn.writeInt32(int32(len(body) + 4))
+	cn.wr.Write(body)
+	return cn.wr.Flush()
+}
+
+// readMessage reads a single backend message and returns its type byte
+// and body.
+func (cn *conn) readMessage() (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(cn.rd, header); err != nil {
+		return 0, nil, err
+	}
+	typ := header[0]
+	length := binary.BigEndian.Uint32(header[1:]) - 4
+	body := make([]byte, length)
+	if length > 0 {
+		if _, err := io.ReadFull(cn.rd, body); err != nil {
+			return 0, nil, err
+		}
+	}
+	return typ, body, nil
+}
+
+func (cn *conn) writeTerminate() error {
+	return cn.writeMessage(msgTerminate, nil)
+}
+
+//
+// startup / authentication
+//
+
+func (cn *conn) startup(opt *Options) error {
+	body := make([]byte, 0, 64)
+	body = append(body, 0, 3, 0, 0) // protocol version 3.0
+
+	writePair := func(k, v string) {
+		body = append(body, k...)
+		body = append(body, 0)
+		body = append(body, v...)
+		body = append(body, 0)
+	}
+	writePair("user", opt.User)
+	if opt.Database != "" {
+		writePair("database", opt.Database)
+	}
+	body = append(body, 0)
+
+	if err := cn.writeMessage(0, body); err != nil {
+		return err
+	}
+
+	for {
+		typ, msg, err := cn.readMessage()
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case msgAuthentication:
+			done, err := cn.handleAuth(msg, opt)
+			if err != nil {
+				return err
+			}
+			if done {
+				continue
+			}
+		case msgParameterStatus, msgBackendKeyData:
+			if typ == msgBackendKeyData {
+				cn.processID = int32(binary.BigEndian.Uint32(msg[0:4]))
+				cn.secretKey = int32(binary.BigEndian.Uint32(msg[4:8]))
+			}
+		case msgErrorResponse:
+			return errorFromFields(parseErrorFields(msg))
+		case msgReadyForQuery:
+			return nil
+		default:
+			return fmt.Errorf("pg: startup: unexpected message %q", typ)
+		}
+	}
+}
+
+func (cn *conn) handleAuth(msg []byte, opt *Options) (bool, error) {
+	code := binary.BigEndian.Uint32(msg[0:4])
+	switch code {
+	case 0: // AuthenticationOk
+		return true, nil
+	case 3: // AuthenticationCleartextPassword
+		return true, cn.writeMessage('p', append([]byte(opt.Password), 0))
+	case 5: // AuthenticationMD5Password
+		salt := msg[4:8]
+		sum := md5.Sum([]byte(opt.Password + opt.User))
+		sum2 := md5.Sum([]byte(fmt.Sprintf("%x", sum) + string(salt)))
+		pass := "md5" + fmt.Sprintf("%x", sum2)
+		return true, cn.writeMessage('p', append([]byte(pass), 0))
+	default:
+		return true, fmt.Errorf("pg: unsupported auth method %d", code)
+	}
+}
+
+func parseErrorFields(msg []byte) map[byte]string {
+	fields := make(map[byte]string)
+	for len(msg) > 0 && msg[0] != 0 {
+		k := msg[0]
+		i := 1
+		for i < len(msg) && msg[i] != 0 {
+			i++
+		}
+		fields[k] = string(msg[1:i])
+		msg = msg[i+1:]
+	}
+	return fields
+}
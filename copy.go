@@ -0,0 +1,143 @@
+package pg
+
+import (
+	"io"
+)
+
+// CopyFrom bulk-loads the COPY data read from r (already formatted as
+// the backend expects, e.g. tab-separated text) using query, which
+// must be a "COPY ... FROM STDIN" statement.
+func (db *DB) CopyFrom(r io.Reader, query string) (Result, error) {
+	cn, err := db.pool.Get()
+	if err != nil {
+		return Result{}, err
+	}
+	defer db.pool.Put(cn)
+	return copyFrom(cn, r, query)
+}
+
+// CopyTo streams the result of query, which must be a
+// "COPY ... TO STDOUT" statement, to w.
+func (db *DB) CopyTo(w io.WriteCloser, query string) (Result, error) {
+	cn, err := db.pool.Get()
+	if err != nil {
+		return Result{}, err
+	}
+	defer db.pool.Put(cn)
+	return copyTo(cn, w, query)
+}
+
+func copyFrom(cn *conn, r io.Reader, query string) (Result, error) {
+	body := append([]byte(query), 0)
+	if err := cn.writeMessage(msgQuery, body); err != nil {
+		return Result{}, err
+	}
+
+	// Wait for CopyInResponse before streaming data.
+	for {
+		typ, msg, err := cn.readMessage()
+		if err != nil {
+			return Result{}, err
+		}
+		switch typ {
+		case msgCopyInResponse:
+			goto stream
+		case msgErrorResponse:
+			return drainToReady(cn, errorFromFields(parseErrorFields(msg)))
+		}
+	}
+
+stream:
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if werr := cn.writeMessage(msgCopyData, buf[:n]); werr != nil {
+				return Result{}, werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			cn.writeMessage(msgCopyFail, append([]byte(err.Error()), 0))
+			return drainToReady(cn, err)
+		}
+	}
+
+	if err := cn.writeMessage(msgCopyDone, nil); err != nil {
+		return Result{}, err
+	}
+
+	return readUntilReady(cn)
+}
+
+func copyTo(cn *conn, w io.WriteCloser, query string) (Result, error) {
+	body := append([]byte(query), 0)
+	if err := cn.writeMessage(msgQuery, body); err != nil {
+		return Result{}, err
+	}
+
+	var res Result
+	var firstErr error
+	for {
+		typ, msg, err := cn.readMessage()
+		if err != nil {
+			return Result{}, err
+		}
+		switch typ {
+		case msgCopyOutResponse:
+		case msgCopyData:
+			if firstErr == nil {
+				if _, werr := w.Write(msg); werr != nil {
+					firstErr = werr
+				}
+			}
+		case msgCopyDone:
+		case msgCommandComplete:
+			res.tag = string(trimNull(msg))
+		case msgErrorResponse:
+			if firstErr == nil {
+				firstErr = errorFromFields(parseErrorFields(msg))
+			}
+		case msgReadyForQuery:
+			return res, firstErr
+		}
+	}
+}
+
+// drainToReady reads (and discards) messages up to and including
+// ReadyForQuery, then returns err unchanged. Used to resynchronize the
+// protocol after an error aborts a COPY in progress.
+func drainToReady(cn *conn, err error) (Result, error) {
+	for {
+		typ, _, rerr := cn.readMessage()
+		if rerr != nil {
+			return Result{}, rerr
+		}
+		if typ == msgReadyForQuery {
+			return Result{}, err
+		}
+	}
+}
+
+func readUntilReady(cn *conn) (Result, error) {
+	var res Result
+	var firstErr error
+	for {
+		typ, msg, err := cn.readMessage()
+		if err != nil {
+			return Result{}, err
+		}
+		switch typ {
+		case msgCommandComplete:
+			res.tag = string(trimNull(msg))
+		case msgErrorResponse:
+			if firstErr == nil {
+				firstErr = errorFromFields(parseErrorFields(msg))
+			}
+		case msgReadyForQuery:
+			return res, firstErr
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package pg
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// copyBinaryHeader is the fixed 19-byte preamble of PostgreSQL's binary
+// COPY format: an 11-byte signature, a 4-byte flags field (always 0,
+// since this package never sets the deprecated OID-inclusion bit) and
+// a 4-byte header extension length (always 0, since there is none).
+var copyBinaryHeader = []byte("PGCOPY\n\377\r\n\000\000\000\000\000\000\000\000\000")
+
+// copyBinaryTrailer is the int16(-1) field count that terminates a
+// binary COPY stream.
+var copyBinaryTrailer = []byte{0xff, 0xff}
+
+// CopyFromRows bulk-loads rows into table's columns using PostgreSQL's
+// binary COPY format. Each value is encoded in binary by
+// copyEncodeBinary (binaryEncoder, then driver.Valuer, then a built-in
+// type switch). CopyFromRows drains rows until it is closed, then waits
+// for the COPY to complete; if a value cannot be encoded, it aborts the
+// COPY and returns the error without reading the remaining rows.
+func (db *DB) CopyFromRows(rows <-chan []interface{}, table string, columns []string) (Result, error) {
+	cn, err := db.pool.Get()
+	if err != nil {
+		return Result{}, err
+	}
+	defer db.pool.Put(cn)
+
+	quotedCols := make([]string, len(columns))
+	for i, col := range columns {
+		quotedCols[i] = quoteIdent(col)
+	}
+	query := fmt.Sprintf(
+		"COPY %s (%s) FROM STDIN BINARY",
+		quoteIdent(table), strings.Join(quotedCols, ", "),
+	)
+	return copyFromRows(cn, rows, query)
+}
+
+func copyFromRows(cn *conn, rows <-chan []interface{}, query string) (Result, error) {
+	body := append([]byte(query), 0)
+	if err := cn.writeMessage(msgQuery, body); err != nil {
+		return Result{}, err
+	}
+
+	for {
+		typ, msg, err := cn.readMessage()
+		if err != nil {
+			return Result{}, err
+		}
+		switch typ {
+		case msgCopyInResponse:
+			goto stream
+		case msgErrorResponse:
+			return drainToReady(cn, errorFromFields(parseErrorFields(msg)))
+		}
+	}
+
+stream:
+	if err := cn.writeMessage(msgCopyData, copyBinaryHeader); err != nil {
+		return Result{}, err
+	}
+
+	for row := range rows {
+		buf := make([]byte, 2, 64)
+		n := int16(len(row))
+		buf[0], buf[1] = byte(n>>8), byte(n)
+
+		for _, v := range row {
+			data, isNull, err := copyEncodeBinary(v)
+			if err != nil {
+				cn.writeMessage(msgCopyFail, append([]byte(err.Error()), 0))
+				return drainToReady(cn, err)
+			}
+			if isNull {
+				buf = append(buf, 0xff, 0xff, 0xff, 0xff)
+				continue
+			}
+			l := int32(len(data))
+			buf = append(buf, byte(l>>24), byte(l>>16), byte(l>>8), byte(l))
+			buf = append(buf, data...)
+		}
+
+		if err := cn.writeMessage(msgCopyData, buf); err != nil {
+			return Result{}, err
+		}
+	}
+
+	if err := cn.writeMessage(msgCopyData, copyBinaryTrailer); err != nil {
+		return Result{}, err
+	}
+	if err := cn.writeMessage(msgCopyDone, nil); err != nil {
+		return Result{}, err
+	}
+
+	return readUntilReady(cn)
+}
+
+// copyEncodeBinary renders v in PostgreSQL's binary wire format. It
+// handles bool, string, []byte, the integer and float types, time.Time,
+// time.Duration and binaryEncoder/driver.Valuer values; any other type
+// (slices, maps, pointers, ...) is rejected with an error.
+func copyEncodeBinary(v interface{}) (data []byte, isNull bool, err error) {
+	if v == nil {
+		return nil, true, nil
+	}
+	if be, ok := v.(binaryEncoder); ok {
+		return be.encodeBinary(), false, nil
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		val, err := valuer.Value()
+		if err != nil {
+			return nil, false, err
+		}
+		if val == nil {
+			return nil, true, nil
+		}
+		return copyEncodeBinary(val)
+	}
+
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return []byte{1}, false, nil
+		}
+		return []byte{0}, false, nil
+	case string:
+		return []byte(v), false, nil
+	case []byte:
+		return v, false, nil
+	case int:
+		return copyEncodeInt(int64(v), 8), false, nil
+	case int8:
+		return copyEncodeInt(int64(v), 2), false, nil
+	case int16:
+		return copyEncodeInt(int64(v), 2), false, nil
+	case int32:
+		return copyEncodeInt(int64(v), 4), false, nil
+	case int64:
+		return copyEncodeInt(v, 8), false, nil
+	case uint:
+		return copyEncodeInt(int64(v), 8), false, nil
+	case uint8:
+		return copyEncodeInt(int64(v), 2), false, nil
+	case uint16:
+		return copyEncodeInt(int64(v), 2), false, nil
+	case uint32:
+		return copyEncodeInt(int64(v), 4), false, nil
+	case uint64:
+		return copyEncodeInt(int64(v), 8), false, nil
+	case float32:
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, math.Float32bits(v))
+		return b, false, nil
+	case float64:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, math.Float64bits(v))
+		return b, false, nil
+	case time.Time:
+		return copyEncodeTimestamp(v), false, nil
+	case time.Duration:
+		return DurationToInterval(v).encodeBinary(), false, nil
+	default:
+		return nil, false, fmt.Errorf("pg: CopyFromRows: unsupported type %T", v)
+	}
+}
+
+func copyEncodeInt(n int64, width int) []byte {
+	b := make([]byte, width)
+	switch width {
+	case 2:
+		binary.BigEndian.PutUint16(b, uint16(n))
+	case 4:
+		binary.BigEndian.PutUint32(b, uint32(n))
+	case 8:
+		binary.BigEndian.PutUint64(b, uint64(n))
+	}
+	return b
+}
+
+func copyEncodeTimestamp(t time.Time) []byte {
+	var micros int64
+	switch t {
+	case PosInfinityTime:
+		micros = math.MaxInt64
+	case NegInfinityTime:
+		micros = math.MinInt64
+	default:
+		micros = t.Sub(pgEpoch).Microseconds()
+	}
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(micros))
+	return b
+}
+
+// CopyToRows streams the result of query (a "COPY ... TO STDOUT
+// BINARY" statement) row by row, calling dst with each row's raw
+// column bytes (nil for SQL NULL). The binary COPY stream carries no
+// per-value type tag, so CopyToRows cannot decode a column into
+// anything more specific than its wire bytes. Decode reads text format
+// only, so it applies directly only to columns whose binary and text
+// forms are the same (such as text); other columns are left to the
+// caller to decode from their binary form.
+func (db *DB) CopyToRows(dst func([]interface{}) error, query string) (Result, error) {
+	cn, err := db.pool.Get()
+	if err != nil {
+		return Result{}, err
+	}
+	defer db.pool.Put(cn)
+	return copyToRows(cn, dst, query)
+}
+
+func copyToRows(cn *conn, dst func([]interface{}) error, query string) (Result, error) {
+	body := append([]byte(query), 0)
+	if err := cn.writeMessage(msgQuery, body); err != nil {
+		return Result{}, err
+	}
+
+	var res Result
+	var firstErr error
+	var buf []byte
+	headerConsumed := false
+	done := false
+
+	for {
+		typ, msg, err := cn.readMessage()
+		if err != nil {
+			return Result{}, err
+		}
+		switch typ {
+		case msgCopyOutResponse:
+		case msgCopyData:
+			buf = append(buf, msg...)
+
+			if !headerConsumed {
+				if len(buf) < len(copyBinaryHeader) {
+					continue
+				}
+				buf = buf[len(copyBinaryHeader):]
+				headerConsumed = true
+			}
+
+			for !done {
+				row, n, isTrailer, perr := parseCopyBinaryRow(buf)
+				if perr != nil {
+					if firstErr == nil {
+						firstErr = perr
+					}
+					done = true
+					break
+				}
+				if n == 0 {
+					break // not enough data buffered for a full row yet
+				}
+				buf = buf[n:]
+				if isTrailer {
+					done = true
+					break
+				}
+				if firstErr == nil {
+					if derr := dst(row); derr != nil {
+						firstErr = derr
+					}
+				}
+			}
+		case msgCopyDone:
+		case msgCommandComplete:
+			res.tag = string(trimNull(msg))
+		case msgErrorResponse:
+			if firstErr == nil {
+				firstErr = errorFromFields(parseErrorFields(msg))
+			}
+		case msgReadyForQuery:
+			return res, firstErr
+		}
+	}
+}
+
+// parseCopyBinaryRow parses one row (field count, then length-prefixed
+// field data) from the front of buf. n is the number of bytes
+// consumed; n == 0 means buf doesn't yet hold a complete row and the
+// caller should wait for more CopyData.
+func parseCopyBinaryRow(buf []byte) (row []interface{}, n int, isTrailer bool, err error) {
+	if len(buf) < 2 {
+		return nil, 0, false, nil
+	}
+
+	fieldCount := int16(binary.BigEndian.Uint16(buf[0:2]))
+	if fieldCount == -1 {
+		return nil, 2, true, nil
+	}
+	if fieldCount < 0 {
+		return nil, 0, false, fmt.Errorf("pg: invalid binary COPY row header")
+	}
+
+	off := 2
+	row = make([]interface{}, fieldCount)
+	for i := 0; i < int(fieldCount); i++ {
+		if len(buf) < off+4 {
+			return nil, 0, false, nil
+		}
+		l := int32(binary.BigEndian.Uint32(buf[off : off+4]))
+		off += 4
+		if l < 0 {
+			continue
+		}
+		if len(buf) < off+int(l) {
+			return nil, 0, false, nil
+		}
+		row[i] = append([]byte(nil), buf[off:off+int(l)]...)
+		off += int(l)
+	}
+	return row, off, false, nil
+}
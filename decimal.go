@@ -0,0 +1,266 @@
+package pg
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an arbitrary-precision decimal number: Unscaled *
+// 10^Exponent, with NaN set for PostgreSQL's "NaN" numeric value.
+// Unlike float32/float64, it round-trips every value PostgreSQL's
+// numeric type can hold, including ones with more significant digits
+// than an IEEE-754 double can represent. Users of a richer decimal
+// package (e.g. shopspring/decimal) can drop their own type in instead,
+// as long as it implements driver.Valuer and sql.Scanner.
+type Decimal struct {
+	Unscaled *big.Int
+	Exponent int32
+	NaN      bool
+}
+
+// NewDecimal returns the Decimal representing unscaled * 10^exponent.
+func NewDecimal(unscaled *big.Int, exponent int32) Decimal {
+	return Decimal{Unscaled: unscaled, Exponent: exponent}
+}
+
+// DecimalNaN is the Decimal representation of PostgreSQL's numeric NaN.
+var DecimalNaN = Decimal{NaN: true}
+
+// String renders d the way PostgreSQL does: plain decimal notation,
+// never scientific.
+func (d Decimal) String() string {
+	if d.NaN {
+		return "NaN"
+	}
+	if d.Unscaled == nil {
+		return "0"
+	}
+
+	neg := d.Unscaled.Sign() < 0
+	s := new(big.Int).Abs(d.Unscaled).String()
+
+	switch {
+	case d.Exponent > 0:
+		s += strings.Repeat("0", int(d.Exponent))
+	case d.Exponent < 0:
+		dscale := int(-d.Exponent)
+		for len(s) <= dscale {
+			s = "0" + s
+		}
+		s = s[:len(s)-dscale] + "." + s[len(s)-dscale:]
+	}
+
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// ParseDecimal parses a PostgreSQL text-format numeric value, e.g.
+// "-123.4560" or "NaN".
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "NaN") {
+		return Decimal{NaN: true}, nil
+	}
+
+	neg := false
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+
+	unscaled, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("pg: invalid numeric %q", s)
+	}
+	if neg {
+		unscaled.Neg(unscaled)
+	}
+
+	return Decimal{Unscaled: unscaled, Exponent: -int32(len(fracPart))}, nil
+}
+
+// Scan implements sql.Scanner, so Decode routes to it automatically the
+// same way it does for sql.NullString and friends.
+func (d *Decimal) Scan(value interface{}) error {
+	if value == nil {
+		*d = Decimal{}
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("pg: Decimal.Scan: unsupported source %T", value)
+	}
+	dec, err := ParseDecimal(string(b))
+	if err != nil {
+		return err
+	}
+	*d = dec
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+const (
+	numericPos int16 = 0x0000
+	numericNeg int16 = 0x4000
+	numericNaN int16 = -16384 // bit pattern 0xC000
+)
+
+// encodeBinary implements binaryEncoder using PostgreSQL's numeric wire
+// format, in this order: ndigits and weight (int16 each), a sign field
+// (int16, one of numericPos/numericNeg/numericNaN), dscale (int16) and
+// ndigits base-10000 digits (int16 each).
+func (d Decimal) encodeBinary() []byte {
+	if d.NaN {
+		return encodeNumericHeader(0, 0, numericNaN, 0, nil)
+	}
+
+	unscaled := d.Unscaled
+	if unscaled == nil {
+		unscaled = new(big.Int)
+	}
+
+	sign := numericPos
+	abs := unscaled
+	if unscaled.Sign() < 0 {
+		sign = numericNeg
+		abs = new(big.Int).Abs(unscaled)
+	}
+
+	digitsStr := ""
+	if abs.Sign() != 0 {
+		digitsStr = abs.String()
+	}
+
+	var intDigits, fracDigits string
+	dscale := int16(0)
+	if d.Exponent >= 0 {
+		intDigits = digitsStr + strings.Repeat("0", int(d.Exponent))
+	} else {
+		n := int(-d.Exponent)
+		dscale = int16(n)
+		if len(digitsStr) > n {
+			intDigits = digitsStr[:len(digitsStr)-n]
+			fracDigits = digitsStr[len(digitsStr)-n:]
+		} else {
+			fracDigits = strings.Repeat("0", n-len(digitsStr)) + digitsStr
+		}
+	}
+
+	intGroups := groupDigits(intDigits, true)
+	fracGroups := groupDigits(fracDigits, false)
+
+	weight := int16(len(intGroups) - 1)
+	digits := append(append([]int16{}, intGroups...), fracGroups...)
+
+	return encodeNumericHeader(int16(len(digits)), weight, sign, dscale, digits)
+}
+
+func (d Decimal) pgOID() int32 {
+	return oidNumeric
+}
+
+// groupDigits splits a run of decimal digits into NBASE (base-10000)
+// groups of 4, padding with zeros on the left for an integer part or
+// on the right for a fractional part so the length divides evenly.
+func groupDigits(s string, padLeft bool) []int16 {
+	if s == "" {
+		return nil
+	}
+	if rem := len(s) % 4; rem != 0 {
+		pad := strings.Repeat("0", 4-rem)
+		if padLeft {
+			s = pad + s
+		} else {
+			s = s + pad
+		}
+	}
+
+	groups := make([]int16, len(s)/4)
+	for i := range groups {
+		n, _ := strconv.Atoi(s[i*4 : i*4+4])
+		groups[i] = int16(n)
+	}
+	return groups
+}
+
+func encodeNumericHeader(ndigits, weight, sign, dscale int16, digits []int16) []byte {
+	b := make([]byte, 8+len(digits)*2)
+	binary.BigEndian.PutUint16(b[0:2], uint16(ndigits))
+	binary.BigEndian.PutUint16(b[2:4], uint16(weight))
+	binary.BigEndian.PutUint16(b[4:6], uint16(sign))
+	binary.BigEndian.PutUint16(b[6:8], uint16(dscale))
+	for i, d := range digits {
+		binary.BigEndian.PutUint16(b[8+i*2:10+i*2], uint16(d))
+	}
+	return b
+}
+
+// decodeDecimalBinary decodes the wire format written by encodeBinary.
+// It's reached through extendedQuery, which asks Bind for this column
+// in binary (see resultFormatOf) whenever the column's OID is
+// oidNumeric.
+func decodeDecimalBinary(b []byte) (Decimal, error) {
+	if len(b) < 8 {
+		return Decimal{}, fmt.Errorf("pg: invalid binary numeric (%d bytes)", len(b))
+	}
+	ndigits := int(binary.BigEndian.Uint16(b[0:2]))
+	weight := int16(binary.BigEndian.Uint16(b[2:4]))
+	sign := int16(binary.BigEndian.Uint16(b[4:6]))
+	dscale := int(binary.BigEndian.Uint16(b[6:8]))
+
+	if sign == numericNaN {
+		return Decimal{NaN: true}, nil
+	}
+	if len(b) < 8+ndigits*2 {
+		return Decimal{}, fmt.Errorf("pg: invalid binary numeric: short digit array")
+	}
+
+	var digits strings.Builder
+	for i := 0; i < ndigits; i++ {
+		off := 8 + i*2
+		fmt.Fprintf(&digits, "%04d", binary.BigEndian.Uint16(b[off:off+2]))
+	}
+	total := digits.String()
+
+	pointPos := (int(weight) + 1) * 4
+	var intPart, fracPart string
+	switch {
+	case pointPos <= 0:
+		fracPart = strings.Repeat("0", -pointPos) + total
+	case pointPos >= len(total):
+		intPart = total + strings.Repeat("0", pointPos-len(total))
+	default:
+		intPart, fracPart = total[:pointPos], total[pointPos:]
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	for len(fracPart) < dscale {
+		fracPart += "0"
+	}
+	fracPart = fracPart[:dscale]
+
+	s := intPart
+	if dscale > 0 {
+		s += "." + fracPart
+	}
+	if sign == numericNeg {
+		s = "-" + s
+	}
+	return ParseDecimal(s)
+}
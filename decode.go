@@ -0,0 +1,301 @@
+package pg
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decode scans the raw (text-format) column value b into dst. b is nil
+// for SQL NULL. dst must be a non-nil pointer; anything else is a
+// programming error and is reported as such rather than silently
+// ignored.
+func Decode(dst interface{}, b []byte) error {
+	if dst == nil {
+		return fmt.Errorf("pg: Decode(nil)")
+	}
+
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("pg: Decode(nonsettable %T)", dst)
+	}
+
+	if scanner, ok := dst.(sql.Scanner); ok {
+		if b == nil {
+			return scanner.Scan(nil)
+		}
+		return scanner.Scan(b)
+	}
+
+	return decodeValue(v.Elem(), b)
+}
+
+func decodeValue(v reflect.Value, b []byte) error {
+	switch dst := v.Addr().Interface().(type) {
+	case *time.Time:
+		if b == nil {
+			*dst = time.Time{}
+			return nil
+		}
+		tm, err := ParseTime(string(b))
+		if err != nil {
+			return err
+		}
+		*dst = tm
+		return nil
+	case *time.Duration:
+		if b == nil {
+			*dst = 0
+			return nil
+		}
+		d, err := ParseInterval(string(b))
+		if err != nil {
+			return err
+		}
+		dur, err := d.Duration()
+		if err != nil {
+			return err
+		}
+		*dst = dur
+		return nil
+	case *Interval:
+		if b == nil {
+			*dst = Interval{}
+			return nil
+		}
+		iv, err := ParseInterval(string(b))
+		if err != nil {
+			return err
+		}
+		*dst = iv
+		return nil
+	}
+
+	if b == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(v.Elem(), b)
+	case reflect.Bool:
+		n, err := strconv.ParseBool(string(b))
+		if err != nil {
+			return err
+		}
+		v.SetBool(n)
+		return nil
+	case reflect.String:
+		v.SetString(string(b))
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(string(b), 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(string(b), 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Float32:
+		n, err := strconv.ParseFloat(string(b), 32)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+		return nil
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(string(b), 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			bs, err := decodeBytea(b)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(bs)
+			return nil
+		}
+		elems := parseArray(b)
+		out := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, elem := range elems {
+			var eb []byte
+			if elem.valid {
+				eb = elem.data
+			}
+			if err := decodeValue(out.Index(i), eb); err != nil {
+				return err
+			}
+		}
+		v.Set(out)
+		return nil
+	case reflect.Map:
+		m := parseHstore(b)
+		out := reflect.MakeMapWithSize(v.Type(), len(m))
+		for k, val := range m {
+			out.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(val))
+		}
+		v.Set(out)
+		return nil
+	case reflect.Struct:
+		return json.Unmarshal(b, v.Addr().Interface())
+	}
+
+	return fmt.Errorf("pg: unsupported dst: %s", v.Type())
+}
+
+// decodeBytea decodes a PostgreSQL text-format bytea value: the
+// "\x"-prefixed hex encoding bytea_output=hex (the default since 9.0)
+// produces, falling back to the legacy backslash-escape format (octal
+// escapes like \NNN, "\\" for a literal backslash, everything else
+// copied through as-is) for servers configured with bytea_output=escape.
+func decodeBytea(b []byte) ([]byte, error) {
+	if len(b) >= 2 && b[0] == '\\' && b[1] == 'x' {
+		out := make([]byte, hex.DecodedLen(len(b)-2))
+		if _, err := hex.Decode(out, b[2:]); err != nil {
+			return nil, fmt.Errorf("pg: invalid bytea hex encoding: %s", err)
+		}
+		return out, nil
+	}
+
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		if c != '\\' {
+			out = append(out, c)
+			continue
+		}
+		switch {
+		case i+1 < len(b) && b[i+1] == '\\':
+			out = append(out, '\\')
+			i++
+		case i+3 < len(b):
+			n, err := strconv.ParseUint(string(b[i+1:i+4]), 8, 8)
+			if err != nil {
+				return nil, fmt.Errorf("pg: invalid bytea escape %q", b[i:i+4])
+			}
+			out = append(out, byte(n))
+			i += 3
+		default:
+			return nil, fmt.Errorf("pg: invalid bytea escape at %q", b[i:])
+		}
+	}
+	return out, nil
+}
+
+type arrayElem struct {
+	data  []byte
+	valid bool
+}
+
+// parseArray parses a PostgreSQL array literal such as `{1,2,3}` or
+// `{"foo\n","bar {}"}` into its top-level elements. Nested arrays are
+// not supported.
+func parseArray(b []byte) []arrayElem {
+	s := strings.TrimSpace(string(b))
+	s = strings.TrimPrefix(s, "{")
+	s = strings.TrimSuffix(s, "}")
+	if s == "" {
+		return nil
+	}
+
+	var elems []arrayElem
+	var cur []byte
+	quoted := false
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			cur = append(cur, c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			elems = append(elems, toArrayElem(cur))
+			cur = nil
+		default:
+			cur = append(cur, c)
+		}
+	}
+	elems = append(elems, toArrayElem(cur))
+	return elems
+}
+
+func toArrayElem(b []byte) arrayElem {
+	if string(b) == "NULL" {
+		return arrayElem{}
+	}
+	return arrayElem{data: b, valid: true}
+}
+
+// parseHstore parses a PostgreSQL hstore literal such as
+// `"foo"=>"bar", "baz"=>NULL` into a map.
+func parseHstore(b []byte) map[string]string {
+	m := make(map[string]string)
+	s := string(b)
+
+	var key string
+	var cur []byte
+	quoted := false
+	escaped := false
+	haveKey := false
+
+	flush := func() {
+		if haveKey {
+			m[key] = string(cur)
+		}
+		cur = nil
+		haveKey = false
+	}
+
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case escaped:
+			cur = append(cur, c)
+			escaped = false
+			i++
+		case c == '\\':
+			escaped = true
+			i++
+		case c == '"':
+			quoted = !quoted
+			i++
+		case !quoted && strings.HasPrefix(s[i:], "=>"):
+			key = string(cur)
+			cur = nil
+			haveKey = true
+			i += 2
+		case !quoted && c == ',':
+			flush()
+			i++
+		default:
+			cur = append(cur, c)
+			i++
+		}
+	}
+	flush()
+	return m
+}
@@ -0,0 +1,184 @@
+package pg
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// binaryEncoder is implemented by types that have a canonical
+// PostgreSQL binary wire representation. Prepared-statement parameter
+// binding prefers it over text encoding when available, since some
+// types (interval, numeric) are ambiguous or lossy in text form.
+type binaryEncoder interface {
+	encodeBinary() []byte
+	pgOID() int32
+}
+
+// appendValue appends the text-format SQL representation of v to b,
+// quoting and escaping it as necessary so the result can be substituted
+// directly into a query in place of a `?` placeholder.
+func appendValue(b []byte, v interface{}) []byte {
+	if v == nil {
+		return append(b, "NULL"...)
+	}
+
+	if valuer, ok := v.(driver.Valuer); ok {
+		val, err := valuer.Value()
+		if err != nil {
+			return append(b, "NULL"...)
+		}
+		return appendValue(b, val)
+	}
+
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return append(b, "TRUE"...)
+		}
+		return append(b, "FALSE"...)
+	case string:
+		return appendQuotedString(b, v)
+	case []byte:
+		return appendQuotedString(b, encodeByteaHex(v))
+	case int:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int8:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int16:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int32:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	case uint:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint8:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint16:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint32:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(b, v, 10)
+	case float32:
+		return strconv.AppendFloat(b, float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.AppendFloat(b, v, 'f', -1, 64)
+	case time.Time:
+		return appendQuotedString(b, appendTime(nil, v))
+	case time.Duration:
+		return appendQuotedString(b, DurationToInterval(v).String())
+	case Interval:
+		return appendQuotedString(b, v.String())
+	case map[string]string:
+		return appendQuotedString(b, string(appendHstore(nil, v)))
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return append(b, "NULL"...)
+		}
+		return appendValue(b, rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return appendQuotedString(b, encodeByteaHex(rv.Bytes()))
+		}
+		return appendQuotedString(b, string(appendArray(nil, rv)))
+	case reflect.Map:
+		return appendQuotedString(b, string(appendHstoreReflect(nil, rv)))
+	}
+
+	return appendQuotedString(b, fmt.Sprint(v))
+}
+
+// encodeByteaHex renders b in PostgreSQL's "\x"-prefixed hex bytea
+// format, the form decodeBytea expects back. Unlike treating the bytes
+// as a Go string and single-quote-escaping them, this is safe for
+// arbitrary binary content (embedded NULs included) inside a
+// simple-query text literal.
+func encodeByteaHex(b []byte) string {
+	return `\x` + hex.EncodeToString(b)
+}
+
+func appendQuotedString(b []byte, s string) []byte {
+	b = append(b, '\'')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\'' {
+			b = append(b, '\'', '\'')
+		} else {
+			b = append(b, s[i])
+		}
+	}
+	return append(b, '\'')
+}
+
+// appendArray renders v (a slice or array) as a PostgreSQL array
+// literal, e.g. {1,2,3} or {"foo","bar"}.
+func appendArray(b []byte, v reflect.Value) []byte {
+	b = append(b, '{')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendArrayElem(b, v.Index(i).Interface())
+	}
+	return append(b, '}')
+}
+
+func appendArrayElem(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return appendArrayQuoted(b, v)
+	default:
+		tmp := appendValue(nil, v)
+		// Numbers and booleans never need quoting inside an array.
+		return append(b, tmp...)
+	}
+}
+
+func appendArrayQuoted(b []byte, s string) []byte {
+	b = append(b, '"')
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\\':
+			b = append(b, '\\', s[i])
+		default:
+			b = append(b, s[i])
+		}
+	}
+	return append(b, '"')
+}
+
+// appendHstore renders m as a PostgreSQL hstore literal.
+func appendHstore(b []byte, m map[string]string) []byte {
+	i := 0
+	for k, v := range m {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendArrayQuoted(b, k)
+		b = append(b, '=', '>')
+		b = appendArrayQuoted(b, v)
+		i++
+	}
+	return b
+}
+
+func appendHstoreReflect(b []byte, v reflect.Value) []byte {
+	keys := v.MapKeys()
+	for i, k := range keys {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendArrayQuoted(b, fmt.Sprint(k.Interface()))
+		b = append(b, '=', '>')
+		b = appendArrayQuoted(b, fmt.Sprint(v.MapIndex(k).Interface()))
+	}
+	return b
+}
@@ -0,0 +1,55 @@
+package pg
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoRows is returned by QueryOne and ExecOne when a query returns
+// (or affects) zero rows.
+var ErrNoRows = errors.New("pg: no rows in result set")
+
+// ErrMultiRows is returned by QueryOne and ExecOne when a query returns
+// (or affects) more than one row.
+var ErrMultiRows = errors.New("pg: multiple rows in result set")
+
+// PGError represents an ErrorResponse message received from the
+// PostgreSQL backend. The field names follow the one-letter codes
+// defined by the wire protocol (see "Error and Notice Message Fields"
+// in the PostgreSQL documentation).
+type PGError struct {
+	fields map[byte]string
+}
+
+func (err *PGError) Field(k byte) string {
+	return err.fields[k]
+}
+
+// Field 'C' - SQLSTATE code, see
+// http://www.postgresql.org/docs/current/static/errcodes-appendix.html
+func (err *PGError) Code() string {
+	return err.fields['C']
+}
+
+func (err *PGError) Error() string {
+	return fmt.Sprintf(
+		"%s #%s %s",
+		err.fields['S'], err.fields['C'], err.fields['M'],
+	)
+}
+
+// IntegrityError is returned when the backend reports a constraint
+// violation, i.e. an ErrorResponse whose SQLSTATE class is 23
+// (integrity_constraint_violation: unique_violation, foreign_key_violation,
+// not_null_violation, etc).
+type IntegrityError struct {
+	*PGError
+}
+
+func errorFromFields(fields map[byte]string) error {
+	pgErr := &PGError{fields: fields}
+	if len(pgErr.Code()) >= 2 && pgErr.Code()[:2] == "23" {
+		return &IntegrityError{pgErr}
+	}
+	return pgErr
+}
@@ -0,0 +1,24 @@
+package pg_test
+
+import (
+	"io"
+
+	"gopkg.in/pg.v3"
+)
+
+func pgdb() *pg.DB {
+	return pg.Connect(&pg.Options{
+		User:     "postgres",
+		Database: "test",
+	})
+}
+
+// NopWriteCloser adapts an io.Writer into an io.WriteCloser whose
+// Close is a no-op, for tests that hand CopyTo a *bytes.Buffer.
+type NopWriteCloser struct {
+	io.Writer
+}
+
+func (*NopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,206 @@
+package pg
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval represents a PostgreSQL interval value. Unlike time.Duration
+// it can hold calendar units (months, days) whose length in absolute
+// time depends on the date they're applied to (a "month" is not always
+// the same number of nanoseconds), so it's the lossless representation
+// for intervals that carry a year/month component. Intervals with no
+// such component can be converted to and from time.Duration with
+// Duration and DurationToInterval.
+type Interval struct {
+	Months int64
+	Days   int64
+	Micros int64
+}
+
+// Duration converts iv to a time.Duration, treating a day as exactly 24
+// hours. It returns an error if iv has a non-zero Months component,
+// since "a month" has no fixed length in nanoseconds.
+func (iv Interval) Duration() (time.Duration, error) {
+	if iv.Months != 0 {
+		return 0, fmt.Errorf("pg: interval %q has a months component and can't be represented as time.Duration", iv.String())
+	}
+	return time.Duration(iv.Days)*24*time.Hour + time.Duration(iv.Micros)*time.Microsecond, nil
+}
+
+// DurationToInterval converts d to an Interval with no calendar
+// component, expressed purely in microseconds.
+func DurationToInterval(d time.Duration) Interval {
+	return Interval{Micros: d.Microseconds()}
+}
+
+// String renders iv using the same "N years N mons N days HH:MM:SS"
+// style PostgreSQL itself outputs.
+func (iv Interval) String() string {
+	var parts []string
+
+	if years := iv.Months / 12; years != 0 {
+		parts = append(parts, fmt.Sprintf("%d year%s", years, plural(years)))
+	}
+	if mons := iv.Months % 12; mons != 0 {
+		parts = append(parts, fmt.Sprintf("%d mon%s", mons, plural(mons)))
+	}
+	if iv.Days != 0 {
+		parts = append(parts, fmt.Sprintf("%d day%s", iv.Days, plural(iv.Days)))
+	}
+	if iv.Micros != 0 || len(parts) == 0 {
+		parts = append(parts, formatIntervalTime(iv.Micros))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func plural(n int64) string {
+	if n == 1 || n == -1 {
+		return ""
+	}
+	return "s"
+}
+
+func formatIntervalTime(micros int64) string {
+	sign := ""
+	if micros < 0 {
+		sign = "-"
+		micros = -micros
+	}
+
+	totalSec := micros / 1e6
+	frac := micros % 1e6
+	hh := totalSec / 3600
+	mm := (totalSec % 3600) / 60
+	ss := totalSec % 60
+
+	if frac == 0 {
+		return fmt.Sprintf("%s%02d:%02d:%02d", sign, hh, mm, ss)
+	}
+	return fmt.Sprintf("%s%02d:%02d:%02d.%06d", sign, hh, mm, ss, frac)
+}
+
+// encodeBinary implements binaryEncoder using the wire format prepared
+// statements bind parameters with: microseconds (int64), days (int32)
+// and months (int32), all big-endian.
+func (iv Interval) encodeBinary() []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b[0:8], uint64(iv.Micros))
+	binary.BigEndian.PutUint32(b[8:12], uint32(iv.Days))
+	binary.BigEndian.PutUint32(b[12:16], uint32(iv.Months))
+	return b
+}
+
+func (iv Interval) pgOID() int32 {
+	return oidInterval
+}
+
+// decodeIntervalBinary decodes the wire format written by encodeBinary.
+func decodeIntervalBinary(b []byte) (Interval, error) {
+	if len(b) != 16 {
+		return Interval{}, fmt.Errorf("pg: invalid binary interval (%d bytes)", len(b))
+	}
+	return Interval{
+		Micros: int64(binary.BigEndian.Uint64(b[0:8])),
+		Days:   int64(int32(binary.BigEndian.Uint32(b[8:12]))),
+		Months: int64(int32(binary.BigEndian.Uint32(b[12:16]))),
+	}, nil
+}
+
+// ParseInterval parses a PostgreSQL text-format interval, e.g.
+// "1 year 2 mons 3 days 04:05:06.789" or "-00:30:00".
+func ParseInterval(s string) (Interval, error) {
+	var iv Interval
+
+	fields := strings.Fields(s)
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+
+		if strings.ContainsRune(f, ':') {
+			micros, err := parseIntervalTime(f)
+			if err != nil {
+				return Interval{}, fmt.Errorf("pg: invalid interval %q: %s", s, err)
+			}
+			iv.Micros += micros
+			continue
+		}
+
+		n, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return Interval{}, fmt.Errorf("pg: invalid interval %q", s)
+		}
+		i++
+		if i >= len(fields) {
+			return Interval{}, fmt.Errorf("pg: invalid interval %q: missing unit after %q", s, f)
+		}
+		unit := strings.ToLower(strings.TrimSuffix(fields[i], "s"))
+
+		switch {
+		case unit == "year" || unit == "yr":
+			iv.Months += n * 12
+		case unit == "mon" || unit == "month":
+			iv.Months += n
+		case unit == "day":
+			iv.Days += n
+		default:
+			return Interval{}, fmt.Errorf("pg: invalid interval %q: unknown unit %q", s, fields[i])
+		}
+	}
+
+	return iv, nil
+}
+
+// parseIntervalTime parses "[+-]HH:MM:SS[.ffffff]" (HH may exceed 24)
+// into microseconds.
+func parseIntervalTime(s string) (int64, error) {
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	hh, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	mm, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	secStr := parts[2]
+	var ss int64
+	var nsec int
+	if i := strings.IndexByte(secStr, '.'); i >= 0 {
+		ss, err = strconv.ParseInt(secStr[:i], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		nsec, err = parseFrac(secStr[i+1:])
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		ss, err = strconv.ParseInt(secStr, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	micros := hh*3600e6 + mm*60e6 + ss*1e6 + int64(nsec)/1000
+	if neg {
+		micros = -micros
+	}
+	return micros, nil
+}
@@ -0,0 +1,249 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Notification is a single NOTIFY event delivered to a Listener.
+type Notification struct {
+	Channel string
+	Payload string
+	PID     int32
+}
+
+// Listener receives NOTIFY events on a connection dedicated to LISTEN,
+// created with (*DB).Listen. If the connection is lost, Listener
+// reconnects with exponential backoff, re-issues its LISTEN commands,
+// and delivers a synthetic Notification with an empty Channel so
+// callers can tell a gap may have occurred.
+type Listener struct {
+	db       *DB
+	channels []string
+
+	mu sync.Mutex
+	cn *conn
+
+	notifyCh  chan *Notification
+	pingCh    chan chan error
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+// Listen opens a Listener subscribed to channels.
+func (db *DB) Listen(channels ...string) (*Listener, error) {
+	ln := &Listener{
+		db:       db,
+		channels: append([]string(nil), channels...),
+		notifyCh: make(chan *Notification, 100),
+		pingCh:   make(chan chan error),
+		closeCh:  make(chan struct{}),
+	}
+
+	cn, err := ln.connect()
+	if err != nil {
+		return nil, err
+	}
+	ln.cn = cn
+
+	go ln.run()
+	return ln, nil
+}
+
+// connect dials a fresh connection and issues LISTEN for every channel.
+func (ln *Listener) connect() (*conn, error) {
+	cn, err := dialConn(ln.db.opt)
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range ln.channels {
+		if _, err := simpleQuery(cn, "LISTEN "+quoteIdent(ch), nil, nil); err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+// Notify returns the channel Notifications are delivered on.
+func (ln *Listener) Notify() <-chan *Notification {
+	return ln.notifyCh
+}
+
+// Close stops the Listener and closes its connection.
+func (ln *Listener) Close() error {
+	ln.closeOnce.Do(func() { close(ln.closeCh) })
+	return nil
+}
+
+// Ping round-trips a no-op query over the Listener's connection to
+// check that it is still alive, the same way a caller might health
+// check an idle connection sitting in a pool.
+func (ln *Listener) Ping(ctx context.Context) error {
+	respCh := make(chan error, 1)
+	select {
+	case ln.pingCh <- respCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-ln.closeCh:
+		return fmt.Errorf("pg: listener is closed")
+	}
+
+	select {
+	case err := <-respCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-ln.closeCh:
+		return fmt.Errorf("pg: listener is closed")
+	}
+}
+
+func (ln *Listener) currentConn() *conn {
+	ln.mu.Lock()
+	defer ln.mu.Unlock()
+	return ln.cn
+}
+
+type backendMsg struct {
+	typ byte
+	msg []byte
+	err error
+}
+
+// run owns the Listener's connection for its entire lifetime: it reads
+// NotificationResponse messages off it, serves Ping by writing a query
+// and waiting for the matching ReadyForQuery, and reconnects (with
+// backoff, re-LISTENing, and a synthetic reconnect Notification) if the
+// connection drops.
+func (ln *Listener) run() {
+	for {
+		cn := ln.currentConn()
+
+		msgCh := make(chan backendMsg, 16)
+		go func() {
+			for {
+				typ, msg, err := cn.readMessage()
+				msgCh <- backendMsg{typ: typ, msg: msg, err: err}
+				if err != nil {
+					return
+				}
+			}
+		}()
+
+		var pending chan error
+
+	readLoop:
+		for {
+			select {
+			case m := <-msgCh:
+				if m.err != nil {
+					if pending != nil {
+						pending <- m.err
+						pending = nil
+					}
+					break readLoop
+				}
+				switch m.typ {
+				case msgNotification:
+					n, err := parseNotification(m.msg)
+					if err == nil {
+						select {
+						case ln.notifyCh <- n:
+						case <-ln.closeCh:
+							return
+						}
+					}
+				case msgReadyForQuery:
+					if pending != nil {
+						pending <- nil
+						pending = nil
+					}
+				case msgErrorResponse:
+					if pending != nil {
+						pending <- errorFromFields(parseErrorFields(m.msg))
+						pending = nil
+					}
+				}
+			case respCh := <-ln.pingCh:
+				if err := cn.writeMessage(msgQuery, append([]byte("SELECT 1"), 0)); err != nil {
+					respCh <- err
+					continue
+				}
+				pending = respCh
+			case <-ln.closeCh:
+				cn.Close()
+				return
+			}
+		}
+
+		cn.Close()
+		select {
+		case <-ln.closeCh:
+			return
+		default:
+		}
+
+		newCn, ok := ln.reconnect()
+		if !ok {
+			return
+		}
+
+		ln.mu.Lock()
+		ln.cn = newCn
+		ln.mu.Unlock()
+
+		select {
+		case ln.notifyCh <- &Notification{}:
+		case <-ln.closeCh:
+			return
+		}
+	}
+}
+
+// reconnect retries connect with exponential backoff until it succeeds
+// or the Listener is closed, in which case ok is false.
+func (ln *Listener) reconnect() (cn *conn, ok bool) {
+	const maxBackoff = 30 * time.Second
+	backoff := 250 * time.Millisecond
+
+	for {
+		cn, err := ln.connect()
+		if err == nil {
+			return cn, true
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ln.closeCh:
+			return nil, false
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// parseNotification parses an 'A' NotificationResponse message: an
+// int32 backend PID followed by the null-terminated channel name and
+// payload.
+func parseNotification(msg []byte) (*Notification, error) {
+	if len(msg) < 4 {
+		return nil, fmt.Errorf("pg: invalid notification payload")
+	}
+	pid := int32(be32(msg[0:4]))
+	rest := msg[4:]
+
+	i := indexZero(rest)
+	channel := string(rest[:i])
+	rest = rest[i+1:]
+
+	j := indexZero(rest)
+	payload := string(rest[:j])
+
+	return &Notification{Channel: channel, Payload: payload, PID: pid}, nil
+}
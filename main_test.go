@@ -2,11 +2,14 @@ package pg_test
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
 	"strings"
 	"testing"
@@ -213,6 +216,11 @@ var (
 
 	pgints    pg.Ints
 	pgstrings pg.Strings
+
+	durationv time.Duration
+	intervalv pg.Interval
+
+	decimalv pg.Decimal
 )
 
 type jsonStruct struct {
@@ -339,6 +347,30 @@ var conversionTests = []conversionTest{
 
 	{src: pg.Ints{1, 2, 3}, dst: &pgints},
 	{src: pg.Strings{"hello", "world"}, dst: &pgstrings},
+
+	{src: 90 * time.Minute, dst: &durationv, pgtype: "interval"},
+	{src: -90 * time.Minute, dst: &durationv, pgtype: "interval"},
+	{src: nil, dst: &durationv, pgtype: "interval", wantzero: true},
+	{
+		src:    pg.Interval{Months: 14, Days: 3, Micros: int64(4*time.Hour+5*time.Minute+6*time.Second+789*time.Millisecond) / 1000},
+		dst:    &intervalv,
+		pgtype: "interval",
+	},
+
+	{src: pg.NewDecimal(big.NewInt(123456), -2), dst: &decimalv, pgtype: "numeric(40,10)"},
+	{src: pg.NewDecimal(big.NewInt(-123456), -2), dst: &decimalv, pgtype: "numeric(40,10)"},
+	{src: pg.DecimalNaN, dst: &decimalv, pgtype: "numeric"},
+	{src: pg.NewDecimal(big.NewInt(1), -30), dst: &decimalv, pgtype: "numeric(40,30)"},
+
+	{src: pg.PosInfinityTime, dst: &timev, pgtype: "timestamp"},
+	{src: pg.NegInfinityTime, dst: &timev, pgtype: "timestamp"},
+	{src: pg.PosInfinityTime, dst: &timev, pgtype: "timestamptz"},
+	{src: pg.NegInfinityTime, dst: &timev, pgtype: "timestamptz"},
+
+	// A 5-digit year, large enough that naively widening its binary
+	// microsecond count to nanoseconds overflows int64.
+	{src: time.Date(22001, time.February, 3, 4, 5, 6, 0, time.UTC), dst: &timev, pgtype: "timestamp"},
+	{src: time.Date(22001, time.February, 3, 4, 5, 6, 0, time.UTC), dst: &timev, pgtype: "timestamptz"},
 }
 
 func (t *conversionTest) Assert(c *C, err error) {
@@ -457,6 +489,17 @@ var timeTests = []struct {
 	{"2001-02-03 04:05:06-07:42", time.Date(2001, time.February, 3, 4, 5, 6, 0, time.FixedZone("", -(7*60*60+42*60)))},
 	{"2001-02-03 04:05:06-07:30:09", time.Date(2001, time.February, 3, 4, 5, 6, 0, time.FixedZone("", -(7*60*60+30*60+9)))},
 	{"2001-02-03 04:05:06+07", time.Date(2001, time.February, 3, 4, 5, 6, 0, time.FixedZone("", 7*60*60))},
+
+	// Years outside the usual 4-digit range.
+	{"22001-02-03", time.Date(22001, time.February, 3, 0, 0, 0, 0, time.UTC)},
+
+	// BC dates: "year N BC" is astronomical year -(N-1).
+	{"0001-12-31 BC", time.Date(0, time.December, 31, 0, 0, 0, 0, time.UTC)},
+	{"2001-02-03 BC", time.Date(-2000, time.February, 3, 0, 0, 0, 0, time.UTC)},
+
+	// infinity / -infinity.
+	{"infinity", pg.PosInfinityTime},
+	{"-infinity", pg.NegInfinityTime},
 }
 
 func (t *DBTest) TestTime(c *C) {
@@ -505,3 +548,70 @@ func (t *DBTest) TestCopyTo(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(res.Affected(), Equals, 1000000)
 }
+
+func (t *DBTest) TestCopyRows(c *C) {
+	// len is bigint so its binary width (8 bytes) matches what
+	// CopyFromRows writes for a Go int.
+	_, err := t.db.Exec("CREATE TEMP TABLE test(word text, len bigint)")
+	c.Assert(err, IsNil)
+
+	words := map[string]int{"hello": 5, "world": 5, "foo": 3, "bar": 3}
+
+	rows := make(chan []interface{})
+	go func() {
+		defer close(rows)
+		for word, length := range words {
+			rows <- []interface{}{word, length}
+		}
+	}()
+
+	res, err := t.db.CopyFromRows(rows, "test", []string{"word", "len"})
+	c.Assert(err, IsNil)
+	c.Assert(res.Affected(), Equals, len(words))
+
+	got := make(map[string]int)
+	res, err = t.db.CopyToRows(func(row []interface{}) error {
+		// word is text, whose binary and text formats are identical, so
+		// it can go straight through Decode; len is bigint, whose
+		// 8-byte binary form Decode doesn't understand, so it's parsed
+		// by hand.
+		var word string
+		if err := pg.Decode(&word, row[0].([]byte)); err != nil {
+			return err
+		}
+		length := int64(binary.BigEndian.Uint64(row[1].([]byte)))
+		got[word] = int(length)
+		return nil
+	}, "COPY test TO STDOUT BINARY")
+	c.Assert(err, IsNil)
+	c.Assert(res.Affected(), Equals, len(words))
+	c.Assert(got, DeepEquals, words)
+}
+
+func (t *DBTest) TestListenNotify(c *C) {
+	ln, err := t.db.Listen("test_channel")
+	c.Assert(err, IsNil)
+	defer ln.Close()
+
+	pub := pgdb()
+	defer pub.Close()
+
+	_, err = pub.Exec("NOTIFY test_channel, 'hello'")
+	c.Assert(err, IsNil)
+
+	select {
+	case n := <-ln.Notify():
+		c.Assert(n.Channel, Equals, "test_channel")
+		c.Assert(n.Payload, Equals, "hello")
+	case <-time.After(5 * time.Second):
+		c.Fatal("timed out waiting for notification")
+	}
+}
+
+func (t *DBTest) TestListenerPing(c *C) {
+	ln, err := t.db.Listen("ping_channel")
+	c.Assert(err, IsNil)
+	defer ln.Close()
+
+	c.Assert(ln.Ping(context.Background()), IsNil)
+}
@@ -0,0 +1,10 @@
+package pg
+
+// PostgreSQL built-in type OIDs relevant to binary parameter encoding.
+// See https://www.postgresql.org/docs/current/catalog-pg-type.html.
+const (
+	oidInterval    = 1186
+	oidNumeric     = 1700
+	oidTimestamp   = 1114
+	oidTimestamptz = 1184
+)
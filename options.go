@@ -0,0 +1,53 @@
+package pg
+
+// Options holds the parameters used to establish connections for a *DB.
+type Options struct {
+	// Network is either "tcp" or "unix". Defaults to "tcp".
+	Network string
+	// Host:Port to dial. Host defaults to "localhost", Port to "5432".
+	Host string
+	Port string
+
+	User     string
+	Password string
+	Database string
+
+	// PoolSize is the maximum number of connections kept in the pool.
+	// Defaults to 10.
+	PoolSize int
+}
+
+func (opt *Options) getNetwork() string {
+	if opt.Network == "" {
+		return "tcp"
+	}
+	return opt.Network
+}
+
+func (opt *Options) getHost() string {
+	if opt.Host == "" {
+		return "localhost"
+	}
+	return opt.Host
+}
+
+func (opt *Options) getPort() string {
+	if opt.Port == "" {
+		return "5432"
+	}
+	return opt.Port
+}
+
+func (opt *Options) getAddr() string {
+	if opt.Network == "unix" {
+		return opt.Host
+	}
+	return opt.getHost() + ":" + opt.getPort()
+}
+
+func (opt *Options) getPoolSize() int {
+	if opt.PoolSize == 0 {
+		return 10
+	}
+	return opt.PoolSize
+}
@@ -0,0 +1,292 @@
+package pg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DB is a PostgreSQL client. A DB is safe for concurrent use by
+// multiple goroutines and maintains its own pool of connections.
+type DB struct {
+	opt  *Options
+	pool *pool
+}
+
+// Connect creates a DB that dials connections lazily using opt.
+func Connect(opt *Options) *DB {
+	return &DB{
+		opt:  opt,
+		pool: newPool(opt),
+	}
+}
+
+// Close closes all idle connections in the pool.
+func (db *DB) Close() error {
+	return db.pool.Close()
+}
+
+// Result carries the outcome of a query or command.
+type Result struct {
+	tag string
+}
+
+// Affected returns the number of rows affected by (or returned from)
+// the statement, as reported in the backend's CommandComplete tag. It
+// is 0 for commands that don't report a row count, such as CREATE
+// TABLE.
+func (res Result) Affected() int {
+	fields := strings.Fields(res.tag)
+	if len(fields) == 0 {
+		return 0
+	}
+	n, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Exec executes a query, substituting each `?` placeholder in turn with
+// the text-format encoding of the corresponding element of params. Any
+// rows the query returns are discarded.
+func (db *DB) Exec(query string, params ...interface{}) (Result, error) {
+	cn, err := db.pool.Get()
+	if err != nil {
+		return Result{}, err
+	}
+	defer db.pool.Put(cn)
+	return simpleQuery(cn, query, params, nil)
+}
+
+// ExecOne is like Exec but requires the statement to affect (or
+// return) exactly one row.
+func (db *DB) ExecOne(query string, params ...interface{}) (Result, error) {
+	res, err := db.Exec(query, params...)
+	if err != nil {
+		return res, err
+	}
+	return res, assertOneAffected(res)
+}
+
+// Query executes a query, loading each returned row into model. model
+// is either a Loader or a pointer to a struct whose fields are matched
+// to columns by name.
+func (db *DB) Query(model interface{}, query string, params ...interface{}) (Result, error) {
+	loader, err := loaderFor(model)
+	if err != nil {
+		return Result{}, err
+	}
+
+	cn, err := db.pool.Get()
+	if err != nil {
+		return Result{}, err
+	}
+	defer db.pool.Put(cn)
+	return simpleQuery(cn, query, params, loader)
+}
+
+// QueryOne is like Query but requires the query to return exactly one
+// row.
+func (db *DB) QueryOne(model interface{}, query string, params ...interface{}) (Result, error) {
+	res, err := db.Query(model, query, params...)
+	if err != nil {
+		return res, err
+	}
+	return res, assertOneAffected(res)
+}
+
+func assertOneAffected(res Result) error {
+	switch res.Affected() {
+	case 0:
+		return ErrNoRows
+	case 1:
+		return nil
+	default:
+		return ErrMultiRows
+	}
+}
+
+// formatQuery substitutes each `?` placeholder in query (outside of
+// string literals) with the text-format encoding of the matching
+// element of params, in order.
+func formatQuery(query string, params []interface{}) (string, error) {
+	if len(params) == 0 {
+		return query, nil
+	}
+
+	var b []byte
+	quoted := false
+	pi := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			quoted = !quoted
+			b = append(b, c)
+		case c == '?' && !quoted:
+			if pi >= len(params) {
+				return "", fmt.Errorf("pg: too few params for query %q", query)
+			}
+			b = appendValue(b, params[pi])
+			pi++
+		default:
+			b = append(b, c)
+		}
+	}
+	return string(b), nil
+}
+
+// simpleQuery runs query over the simple query protocol (message type
+// 'Q'), optionally scanning rows into loader.
+func simpleQuery(cn *conn, query string, params []interface{}, loader Loader) (Result, error) {
+	query, err := formatQuery(query, params)
+	if err != nil {
+		return Result{}, err
+	}
+
+	body := append([]byte(query), 0)
+	if err := cn.writeMessage(msgQuery, body); err != nil {
+		return Result{}, err
+	}
+
+	var res Result
+	var cols []resultColumn
+	var firstErr error
+
+	for {
+		typ, msg, err := cn.readMessage()
+		if err != nil {
+			return Result{}, err
+		}
+
+		switch typ {
+		case msgRowDescription:
+			cols = parseRowDescription(msg)
+		case msgDataRow:
+			if loader != nil {
+				loader.New()
+				if err := loadRow(loader, cols, msg); err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}
+		case msgCommandComplete:
+			res.tag = string(trimNull(msg))
+		case msgEmptyQueryResp:
+		case msgErrorResponse:
+			if firstErr == nil {
+				firstErr = errorFromFields(parseErrorFields(msg))
+			}
+		case msgReadyForQuery:
+			return res, firstErr
+		}
+	}
+}
+
+func trimNull(b []byte) []byte {
+	if i := len(b); i > 0 && b[i-1] == 0 {
+		return b[:i-1]
+	}
+	return b
+}
+
+// resultColumn describes one column of a RowDescription: its name, its
+// PostgreSQL type OID and the wire format (0 = text, 1 = binary) the
+// backend will use for it in the following DataRow messages.
+type resultColumn struct {
+	Name   string
+	OID    int32
+	Format int16
+}
+
+func parseRowDescription(msg []byte) []resultColumn {
+	n := int(be16(msg))
+	msg = msg[2:]
+	cols := make([]resultColumn, 0, n)
+	for i := 0; i < n; i++ {
+		end := indexZero(msg)
+		name := string(msg[:end])
+		msg = msg[end+1:]
+		oid := int32(be32(msg[6:10]))
+		format := int16(be16(msg[16:18]))
+		msg = msg[18:]
+		cols = append(cols, resultColumn{Name: name, OID: oid, Format: format})
+	}
+	return cols
+}
+
+func loadRow(loader Loader, cols []resultColumn, msg []byte) error {
+	n := int(be16(msg))
+	msg = msg[2:]
+	for i := 0; i < n; i++ {
+		l := int(int32(be32(msg)))
+		msg = msg[4:]
+		var b []byte
+		if l >= 0 {
+			b = msg[:l]
+			msg = msg[l:]
+		}
+
+		colName := ""
+		if i < len(cols) {
+			colName = cols[i].Name
+			if b != nil && cols[i].Format == 1 {
+				text, err := binaryColumnToText(cols[i].OID, b)
+				if err != nil {
+					return err
+				}
+				b = text
+			}
+		}
+		if err := loader.Load(i, colName, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// binaryColumnToText converts a binary-format column value back into
+// its text-format representation, so the rest of the decode pipeline
+// only ever has to deal with text. Columns whose type has no known
+// binary decoder are returned unchanged (which will usually fail to
+// parse downstream -- callers should not request binary format for
+// types they can't convert here).
+func binaryColumnToText(oid int32, b []byte) ([]byte, error) {
+	switch oid {
+	case oidInterval:
+		iv, err := decodeIntervalBinary(b)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(iv.String()), nil
+	case oidNumeric:
+		dec, err := decodeDecimalBinary(b)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(dec.String()), nil
+	case oidTimestamp:
+		return decodeTimestampBinary(b, false)
+	case oidTimestamptz:
+		return decodeTimestampBinary(b, true)
+	default:
+		return b, nil
+	}
+}
+
+func indexZero(b []byte) int {
+	for i, c := range b {
+		if c == 0 {
+			return i
+		}
+	}
+	return len(b)
+}
+
+func be16(b []byte) uint16 {
+	return uint16(b[0])<<8 | uint16(b[1])
+}
+
+func be32(b []byte) uint32 {
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
@@ -0,0 +1,48 @@
+package pg
+
+// pool is a simple free-list of connections bounded by Options.PoolSize.
+// Connections are dialed lazily on first use and reused across queries.
+type pool struct {
+	opt *Options
+
+	free chan *conn
+}
+
+func newPool(opt *Options) *pool {
+	return &pool{
+		opt:  opt,
+		free: make(chan *conn, opt.getPoolSize()),
+	}
+}
+
+func (p *pool) Get() (*conn, error) {
+	select {
+	case cn := <-p.free:
+		return cn, nil
+	default:
+		return dialConn(p.opt)
+	}
+}
+
+func (p *pool) Put(cn *conn) {
+	select {
+	case p.free <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+func (p *pool) Remove(cn *conn) {
+	cn.Close()
+}
+
+func (p *pool) Close() error {
+	close(p.free)
+	var firstErr error
+	for cn := range p.free {
+		if err := cn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
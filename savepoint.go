@@ -0,0 +1,90 @@
+package pg
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var savepointCounter int64
+
+func nextSavepointName() string {
+	n := atomic.AddInt64(&savepointCounter, 1)
+	return "pg_savepoint_" + strconv.FormatInt(n, 10)
+}
+
+// quoteIdent double-quotes s for use as a SQL identifier, escaping any
+// embedded double quotes.
+func quoteIdent(s string) string {
+	return `"` + strings.Replace(s, `"`, `""`, -1) + `"`
+}
+
+// Savepoint is a named point within a transaction that later statements
+// can partially roll back to without aborting the whole transaction.
+type Savepoint struct {
+	tx   *Tx
+	name string
+}
+
+// Savepoint establishes a new savepoint named name within the
+// transaction.
+func (tx *Tx) Savepoint(name string) (*Savepoint, error) {
+	if _, err := tx.Exec("SAVEPOINT " + quoteIdent(name)); err != nil {
+		return nil, err
+	}
+	return &Savepoint{tx: tx, name: name}, nil
+}
+
+// Rollback rolls the transaction back to sp, undoing anything done
+// since it was established, but leaving the transaction (and anything
+// committed before sp) open and usable.
+func (sp *Savepoint) Rollback() error {
+	_, err := sp.tx.Exec("ROLLBACK TO SAVEPOINT " + quoteIdent(sp.name))
+	return err
+}
+
+// Release forgets sp without undoing anything. Once a transaction ends,
+// all of its savepoints are released implicitly.
+func (sp *Savepoint) Release() error {
+	_, err := sp.tx.Exec("RELEASE SAVEPOINT " + quoteIdent(sp.name))
+	return err
+}
+
+// RunInSavepoint runs fn within a new savepoint: if fn returns an
+// error, everything it did is rolled back to that savepoint and the
+// error is returned, leaving the rest of tx usable; otherwise the
+// savepoint is released and RunInSavepoint returns nil.
+func (tx *Tx) RunInSavepoint(fn func(*Tx) error) error {
+	sp, err := tx.Savepoint(nextSavepointName())
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := sp.Rollback(); rbErr != nil {
+			return rbErr
+		}
+		return err
+	}
+
+	return sp.Release()
+}
+
+// RunInTransaction runs fn within a new transaction: if fn returns an
+// error, the transaction is rolled back and the error is returned;
+// otherwise the transaction is committed.
+func (db *DB) RunInTransaction(fn func(*Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return rbErr
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
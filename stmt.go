@@ -0,0 +1,301 @@
+package pg
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+var stmtCounter int64
+
+func nextStmtName() string {
+	n := atomic.AddInt64(&stmtCounter, 1)
+	return "pg_stmt_" + strconv.FormatInt(n, 10)
+}
+
+// Stmt is a prepared statement bound to a single backend connection.
+type Stmt struct {
+	cn   *conn
+	pool *pool // nil when owned by a Tx; the Tx manages cn's lifecycle.
+	name string
+	cols []resultColumn
+}
+
+// Prepare creates a prepared statement on a dedicated connection. The
+// connection is held until the Stmt is closed.
+func (db *DB) Prepare(query string) (*Stmt, error) {
+	cn, err := db.pool.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	name, cols, err := prepareStmt(cn, query)
+	if err != nil {
+		db.pool.Put(cn)
+		return nil, err
+	}
+
+	return &Stmt{cn: cn, pool: db.pool, name: name, cols: cols}, nil
+}
+
+func prepareStmt(cn *conn, query string) (string, []resultColumn, error) {
+	name := nextStmtName()
+
+	body := make([]byte, 0, len(query)+16)
+	body = append(body, name...)
+	body = append(body, 0)
+	body = append(body, query...)
+	body = append(body, 0)
+	body = append(body, 0, 0) // zero parameter type OIDs (infer)
+	if err := cn.writeMessage(msgParse, body); err != nil {
+		return "", nil, err
+	}
+
+	descBody := append([]byte{'S'}, name...)
+	descBody = append(descBody, 0)
+	if err := cn.writeMessage(msgDescribe, descBody); err != nil {
+		return "", nil, err
+	}
+
+	if err := cn.writeMessage(msgSync, nil); err != nil {
+		return "", nil, err
+	}
+
+	var cols []resultColumn
+	var firstErr error
+	for {
+		typ, msg, err := cn.readMessage()
+		if err != nil {
+			return "", nil, err
+		}
+		switch typ {
+		case msgParseComplete, msgNoData:
+		case msgRowDescription:
+			cols = parseRowDescription(msg)
+		case msgErrorResponse:
+			if firstErr == nil {
+				firstErr = errorFromFields(parseErrorFields(msg))
+			}
+		case 't': // ParameterDescription
+		case msgReadyForQuery:
+			return name, cols, firstErr
+		}
+	}
+}
+
+// Close releases the prepared statement and the connection backing it.
+func (stmt *Stmt) Close() error {
+	body := append([]byte{'S'}, stmt.name...)
+	body = append(body, 0)
+	if err := stmt.cn.writeMessage(msgClose, body); err != nil {
+		return err
+	}
+	if err := stmt.cn.writeMessage(msgSync, nil); err != nil {
+		return err
+	}
+	for {
+		typ, msg, err := stmt.cn.readMessage()
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case msgErrorResponse:
+			return errorFromFields(parseErrorFields(msg))
+		case msgReadyForQuery:
+			if stmt.pool != nil {
+				stmt.pool.Put(stmt.cn)
+			}
+			return nil
+		}
+	}
+}
+
+// Exec runs the prepared statement, discarding any rows it returns.
+func (stmt *Stmt) Exec(params ...interface{}) (Result, error) {
+	return extendedQuery(stmt.cn, stmt.name, stmt.cols, params, nil)
+}
+
+// ExecOne is like Exec but requires exactly one row to be affected.
+func (stmt *Stmt) ExecOne(params ...interface{}) (Result, error) {
+	res, err := stmt.Exec(params...)
+	if err != nil {
+		return res, err
+	}
+	return res, assertOneAffected(res)
+}
+
+// Query runs the prepared statement, loading each row into model.
+func (stmt *Stmt) Query(model interface{}, params ...interface{}) (Result, error) {
+	loader, err := loaderFor(model)
+	if err != nil {
+		return Result{}, err
+	}
+	return extendedQuery(stmt.cn, stmt.name, stmt.cols, params, loader)
+}
+
+// QueryOne is like Query but requires exactly one row to be returned.
+func (stmt *Stmt) QueryOne(model interface{}, params ...interface{}) (Result, error) {
+	res, err := stmt.Query(model, params...)
+	if err != nil {
+		return res, err
+	}
+	return res, assertOneAffected(res)
+}
+
+// resultFormatOf reports the wire format (0 = text, 1 = binary) we
+// should ask the backend to send a column back in. Binary is requested
+// only for types with a known binaryColumnToText decoder, since
+// anything else would reach the text-based decode path undigested.
+func resultFormatOf(col resultColumn) int16 {
+	switch col.OID {
+	case oidInterval, oidNumeric, oidTimestamp, oidTimestamptz:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// extendedQuery binds params to the named prepared statement and
+// executes it over the extended query protocol (Bind/Execute/Sync).
+// cols is the statement's result column list, as learned from the
+// Describe response at Prepare time; per the wire protocol, a
+// statement-level Describe always reports format 0 regardless of what
+// Bind will actually request, so cols[i].Format itself can't be
+// trusted. extendedQuery instead derives the real per-column format
+// from resultFormatOf and carries that decision through to loadRow, so
+// the two agree on what was actually put on the wire.
+func extendedQuery(cn *conn, stmtName string, cols []resultColumn, params []interface{}, loader Loader) (Result, error) {
+	numParams := len(params)
+
+	boundCols := make([]resultColumn, len(cols))
+	for i, col := range cols {
+		col.Format = resultFormatOf(col)
+		boundCols[i] = col
+	}
+
+	body := make([]byte, 0, 64)
+	body = append(body, 0)           // unnamed portal
+	body = append(body, stmtName...) // statement name
+	body = append(body, 0)
+	body = append(body, byte(numParams>>8), byte(numParams))
+	for i := 0; i < numParams; i++ {
+		fmtCode, _ := encodeParam(params[i])
+		body = append(body, byte(fmtCode>>8), byte(fmtCode))
+	}
+	body = append(body, byte(numParams>>8), byte(numParams))
+	for _, p := range params {
+		_, data := encodeParam(p)
+		if data == nil {
+			body = append(body, 0xff, 0xff, 0xff, 0xff) // -1: NULL
+			continue
+		}
+		n := int32(len(data))
+		body = append(body, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+		body = append(body, data...)
+	}
+	body = append(body, byte(len(boundCols)>>8), byte(len(boundCols)))
+	for _, col := range boundCols {
+		body = append(body, byte(col.Format>>8), byte(col.Format))
+	}
+
+	if err := cn.writeMessage(msgBind, body); err != nil {
+		return Result{}, err
+	}
+
+	execBody := append([]byte{0}, 0, 0, 0, 0) // unnamed portal, maxRows=0
+	if err := cn.writeMessage(msgExecute, execBody); err != nil {
+		return Result{}, err
+	}
+	if err := cn.writeMessage(msgSync, nil); err != nil {
+		return Result{}, err
+	}
+
+	var res Result
+	var firstErr error
+	for {
+		typ, msg, err := cn.readMessage()
+		if err != nil {
+			return Result{}, err
+		}
+		switch typ {
+		case msgBindComplete:
+		case msgRowDescription:
+			// The portal's own Describe (unused here) would report
+			// this; the statement-level cols captured at Prepare time
+			// already describe this query's results.
+		case msgDataRow:
+			if loader != nil {
+				loader.New()
+				if err := loadRow(loader, boundCols, msg); err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}
+		case msgCommandComplete:
+			res.tag = string(trimNull(msg))
+		case msgErrorResponse:
+			if firstErr == nil {
+				firstErr = errorFromFields(parseErrorFields(msg))
+			}
+		case msgReadyForQuery:
+			return res, firstErr
+		}
+	}
+}
+
+// encodeParam renders v as a Bind parameter, returning the format code
+// (0 = text, 1 = binary) and the raw wire bytes (nil for SQL NULL).
+// Pointers, slices, arrays and maps are rendered unquoted, unlike
+// appendValue, which produces SQL-literal text for substitution into a
+// query string; any other type falls back to appendValue's text.
+func encodeParam(v interface{}) (int16, []byte) {
+	if v == nil {
+		return 0, nil
+	}
+	// binaryEncoder is checked before driver.Valuer because some types
+	// (e.g. Decimal) implement both: Valuer for text-protocol callers,
+	// binaryEncoder for the unambiguous wire format Bind prefers.
+	if be, ok := v.(binaryEncoder); ok {
+		return 1, be.encodeBinary()
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		val, err := valuer.Value()
+		if err != nil || val == nil {
+			return 0, nil
+		}
+		return encodeParam(val)
+	}
+
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return 0, []byte("t")
+		}
+		return 0, []byte("f")
+	case string:
+		return 0, []byte(v)
+	case []byte:
+		return 1, v
+	case time.Time:
+		return 0, []byte(appendTime(nil, v))
+	case time.Duration:
+		return encodeParam(DurationToInterval(v))
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return 0, nil
+		}
+		return encodeParam(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			return 0, appendArray(nil, rv)
+		}
+	case reflect.Map:
+		return 0, appendHstoreReflect(nil, rv)
+	}
+	return 0, appendValue(nil, v)
+}
@@ -0,0 +1,204 @@
+package pg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PosInfinityTime and NegInfinityTime are the sentinel time.Time values
+// used to represent PostgreSQL's "infinity" and "-infinity" timestamps,
+// which have no finite equivalent. They are ordinary (if extreme)
+// time.Time values, chosen at the edges of the range PostgreSQL itself
+// can represent, so they sort and compare the way callers expect.
+var (
+	PosInfinityTime = time.Date(294276, time.January, 1, 0, 0, 0, 0, time.UTC)
+	NegInfinityTime = time.Date(-4713, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// ParseTime parses a PostgreSQL text-format date or timestamp value,
+// e.g. "2001-02-03", "2001-02-03 04:05:06.123-07:30:09", a BC date such
+// as "0001-12-31 BC", or the special values "infinity" and "-infinity".
+//
+// A bare date (no time-of-day component) is interpreted as UTC, since
+// PostgreSQL's "date" type carries no time zone information. A
+// timestamp without an explicit zone offset is interpreted in
+// time.Local, matching "timestamp without time zone" semantics; a
+// timestamp with an offset is returned in a time.FixedZone for that
+// offset.
+func ParseTime(s string) (time.Time, error) {
+	switch s {
+	case "infinity":
+		return PosInfinityTime, nil
+	case "-infinity":
+		return NegInfinityTime, nil
+	}
+
+	bc := false
+	if strings.HasSuffix(s, " BC") {
+		bc = true
+		s = strings.TrimSuffix(s, " BC")
+	}
+
+	datePart := s
+	restPart := ""
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		datePart = s[:i]
+		restPart = s[i+1:]
+	}
+
+	year, month, day, err := parseDate(datePart)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("pg: invalid time %q: %s", s, err)
+	}
+	if bc {
+		// PostgreSQL's "year N BC" is astronomical year -(N-1): "1 BC"
+		// is year 0, "2 BC" is year -1, and so on.
+		year = -(year - 1)
+	}
+
+	if restPart == "" {
+		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC), nil
+	}
+
+	hour, min, sec, nsec, loc, err := parseTimeOfDay(restPart)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("pg: invalid time %q: %s", s, err)
+	}
+
+	return time.Date(year, time.Month(month), day, hour, min, sec, nsec, loc), nil
+}
+
+func parseDate(s string) (year, month, day int, err error) {
+	parts := strings.SplitN(s, "-", 3)
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("invalid date %q", s)
+	}
+	year, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	month, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	day, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return year, month, day, nil
+}
+
+// parseTimeOfDay parses "HH:MM:SS[.ffffff][(+|-)HH[:MM[:SS]]]".
+func parseTimeOfDay(s string) (hour, min, sec, nsec int, loc *time.Location, err error) {
+	zoneIdx := -1
+	for i := 1; i < len(s); i++ {
+		if s[i] == '+' || s[i] == '-' {
+			zoneIdx = i
+			break
+		}
+	}
+
+	timePart := s
+	zonePart := ""
+	if zoneIdx >= 0 {
+		timePart = s[:zoneIdx]
+		zonePart = s[zoneIdx:]
+	}
+
+	hmsParts := strings.SplitN(timePart, ":", 3)
+	if len(hmsParts) != 3 {
+		return 0, 0, 0, 0, nil, fmt.Errorf("invalid time %q", s)
+	}
+	hour, err = strconv.Atoi(hmsParts[0])
+	if err != nil {
+		return 0, 0, 0, 0, nil, err
+	}
+	min, err = strconv.Atoi(hmsParts[1])
+	if err != nil {
+		return 0, 0, 0, 0, nil, err
+	}
+
+	secStr := hmsParts[2]
+	if i := strings.IndexByte(secStr, '.'); i >= 0 {
+		sec, err = strconv.Atoi(secStr[:i])
+		if err != nil {
+			return 0, 0, 0, 0, nil, err
+		}
+		frac := secStr[i+1:]
+		nsec, err = parseFrac(frac)
+		if err != nil {
+			return 0, 0, 0, 0, nil, err
+		}
+	} else {
+		sec, err = strconv.Atoi(secStr)
+		if err != nil {
+			return 0, 0, 0, 0, nil, err
+		}
+	}
+
+	if zonePart == "" {
+		return hour, min, sec, nsec, time.Local, nil
+	}
+
+	offset, err := parseZoneOffset(zonePart)
+	if err != nil {
+		return 0, 0, 0, 0, nil, err
+	}
+	return hour, min, sec, nsec, time.FixedZone("", offset), nil
+}
+
+// parseFrac converts the digits after a decimal point into nanoseconds,
+// e.g. "1" -> 100000000, "123456" -> 123456000.
+func parseFrac(frac string) (int, error) {
+	n, err := strconv.Atoi(frac)
+	if err != nil {
+		return 0, err
+	}
+	for i := len(frac); i < 9; i++ {
+		n *= 10
+	}
+	return n, nil
+}
+
+// parseZoneOffset parses "(+|-)HH[:MM[:SS]]" into a signed number of
+// seconds east of UTC.
+func parseZoneOffset(s string) (int, error) {
+	sign := 1
+	if s[0] == '-' {
+		sign = -1
+	}
+	s = s[1:]
+
+	parts := strings.Split(s, ":")
+	hh, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, err
+	}
+	mm, ss := 0, 0
+	if len(parts) > 1 {
+		mm, err = strconv.Atoi(parts[1])
+		if err != nil {
+			return 0, err
+		}
+	}
+	if len(parts) > 2 {
+		ss, err = strconv.Atoi(parts[2])
+		if err != nil {
+			return 0, err
+		}
+	}
+	return sign * (hh*60*60 + mm*60 + ss), nil
+}
+
+// appendTime formats t as a PostgreSQL timestamptz text literal.
+func appendTime(b []byte, t time.Time) string {
+	switch t {
+	case PosInfinityTime:
+		return "infinity"
+	case NegInfinityTime:
+		return "-infinity"
+	}
+	return t.Format("2006-01-02 15:04:05.999999-07:00:00")
+}
@@ -0,0 +1,54 @@
+package pg
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// pgEpoch is the zero point ("2000-01-01 00:00:00 UTC") that
+// PostgreSQL's binary timestamp formats count microseconds from.
+var pgEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// decodeTimestampBinary decodes the 8-byte binary wire format used for
+// timestamp and timestamptz columns: a big-endian int64 count of
+// microseconds relative to pgEpoch, with math.MaxInt64/math.MinInt64
+// reserved for "infinity"/"-infinity". withZone controls whether the
+// resulting text carries a zone offset, matching timestamptz's
+// semantics as an instant versus timestamp's zone-less wall-clock
+// semantics. It's reached through extendedQuery, which asks Bind for
+// a column in binary (see resultFormatOf) whenever the column's OID
+// is oidTimestamp or oidTimestamptz.
+func decodeTimestampBinary(b []byte, withZone bool) ([]byte, error) {
+	if len(b) != 8 {
+		return nil, fmt.Errorf("pg: invalid binary timestamp (%d bytes)", len(b))
+	}
+
+	micros := int64(binary.BigEndian.Uint64(b))
+	switch micros {
+	case math.MaxInt64:
+		return []byte("infinity"), nil
+	case math.MinInt64:
+		return []byte("-infinity"), nil
+	}
+
+	// time.Duration is itself an int64 nanosecond count, so scaling
+	// micros up to nanoseconds (by 1000, whether done directly or via a
+	// seconds/microseconds split that's later recombined as Durations)
+	// overflows for anything more than ~292 years out from pgEpoch --
+	// which 5+-digit years and distant BC dates both are. time.Unix
+	// builds the time.Time from a (seconds, nanoseconds) pair without
+	// ever materializing that product, so it has no such ceiling.
+	sec := micros / 1e6
+	usec := micros % 1e6
+	if usec < 0 {
+		sec--
+		usec += 1e6
+	}
+	t := time.Unix(pgEpoch.Unix()+sec, usec*1000).UTC()
+	if withZone {
+		return []byte(appendTime(nil, t)), nil
+	}
+	return []byte(t.Format("2006-01-02 15:04:05.999999")), nil
+}
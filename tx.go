@@ -0,0 +1,82 @@
+package pg
+
+// Tx is an in-progress transaction, pinned to a single connection for
+// its whole lifetime so that statement-local state (prepared
+// statements, savepoints) stays visible until Commit or Rollback.
+type Tx struct {
+	db *DB
+	cn *conn
+}
+
+// Begin starts a transaction on a dedicated connection.
+func (db *DB) Begin() (*Tx, error) {
+	cn, err := db.pool.Get()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := simpleQuery(cn, "BEGIN", nil, nil); err != nil {
+		db.pool.Remove(cn)
+		return nil, err
+	}
+	return &Tx{db: db, cn: cn}, nil
+}
+
+// Commit commits the transaction and returns the connection to the
+// pool.
+func (tx *Tx) Commit() error {
+	_, err := simpleQuery(tx.cn, "COMMIT", nil, nil)
+	tx.db.pool.Put(tx.cn)
+	return err
+}
+
+// Rollback rolls back the transaction and returns the connection to
+// the pool.
+func (tx *Tx) Rollback() error {
+	_, err := simpleQuery(tx.cn, "ROLLBACK", nil, nil)
+	tx.db.pool.Put(tx.cn)
+	return err
+}
+
+// Exec is like (*DB).Exec but runs within the transaction.
+func (tx *Tx) Exec(query string, params ...interface{}) (Result, error) {
+	return simpleQuery(tx.cn, query, params, nil)
+}
+
+// ExecOne is like (*DB).ExecOne but runs within the transaction.
+func (tx *Tx) ExecOne(query string, params ...interface{}) (Result, error) {
+	res, err := tx.Exec(query, params...)
+	if err != nil {
+		return res, err
+	}
+	return res, assertOneAffected(res)
+}
+
+// Query is like (*DB).Query but runs within the transaction.
+func (tx *Tx) Query(model interface{}, query string, params ...interface{}) (Result, error) {
+	loader, err := loaderFor(model)
+	if err != nil {
+		return Result{}, err
+	}
+	return simpleQuery(tx.cn, query, params, loader)
+}
+
+// QueryOne is like (*DB).QueryOne but runs within the transaction.
+func (tx *Tx) QueryOne(model interface{}, query string, params ...interface{}) (Result, error) {
+	res, err := tx.Query(model, query, params...)
+	if err != nil {
+		return res, err
+	}
+	return res, assertOneAffected(res)
+}
+
+// Prepare creates a prepared statement bound to the transaction's
+// connection. The statement is only valid for the lifetime of the
+// transaction; closing it does not release the connection, since the
+// Tx itself owns that.
+func (tx *Tx) Prepare(query string) (*Stmt, error) {
+	name, cols, err := prepareStmt(tx.cn, query)
+	if err != nil {
+		return nil, err
+	}
+	return &Stmt{cn: tx.cn, name: name, cols: cols}, nil
+}
@@ -46,3 +46,54 @@ func (t *TxTest) TestMultiPrepare(c *C) {
 
 	c.Assert(tx.Rollback(), IsNil)
 }
+
+func (t *TxTest) TestSavepointRollback(c *C) {
+	tx, err := t.db.Begin()
+	c.Assert(err, IsNil)
+
+	_, err = tx.Exec(`CREATE TEMP TABLE savepoint_test (id int UNIQUE)`)
+	c.Assert(err, IsNil)
+
+	_, err = tx.Exec(`INSERT INTO savepoint_test VALUES (1)`)
+	c.Assert(err, IsNil)
+
+	sp, err := tx.Savepoint("sp1")
+	c.Assert(err, IsNil)
+
+	_, err = tx.Exec(`INSERT INTO savepoint_test VALUES (1)`)
+	c.Assert(err, FitsTypeOf, &pg.IntegrityError{})
+
+	c.Assert(sp.Rollback(), IsNil)
+
+	// The outer transaction is still usable after rolling back to sp.
+	res, err := tx.Exec(`INSERT INTO savepoint_test VALUES (2)`)
+	c.Assert(err, IsNil)
+	c.Assert(res.Affected(), Equals, 1)
+
+	c.Assert(tx.Rollback(), IsNil)
+}
+
+func (t *TxTest) TestRunInSavepoint(c *C) {
+	tx, err := t.db.Begin()
+	c.Assert(err, IsNil)
+
+	_, err = tx.Exec(`CREATE TEMP TABLE run_in_savepoint_test (id int UNIQUE)`)
+	c.Assert(err, IsNil)
+
+	_, err = tx.Exec(`INSERT INTO run_in_savepoint_test VALUES (1)`)
+	c.Assert(err, IsNil)
+
+	err = tx.RunInSavepoint(func(tx *pg.Tx) error {
+		_, err := tx.Exec(`INSERT INTO run_in_savepoint_test VALUES (1)`)
+		return err
+	})
+	c.Assert(err, FitsTypeOf, &pg.IntegrityError{})
+
+	// The failed savepoint was rolled back automatically; the outer
+	// transaction is still usable.
+	res, err := tx.Exec(`INSERT INTO run_in_savepoint_test VALUES (2)`)
+	c.Assert(err, IsNil)
+	c.Assert(res.Affected(), Equals, 1)
+
+	c.Assert(tx.Rollback(), IsNil)
+}
@@ -0,0 +1,88 @@
+package pg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Loader is implemented by types that want full control over how a
+// query result is scanned into Go values. New is called once to obtain
+// the destination for the current row; Load is then called once per
+// column of that row with the raw text-format bytes received from the
+// backend, or nil for SQL NULL.
+type Loader interface {
+	New() interface{}
+	Load(colIdx int, colName string, b []byte) error
+}
+
+type intoLoader struct {
+	dst interface{}
+}
+
+// LoadInto wraps dst, a pointer to a Go value, so it can be passed to
+// the Query/QueryOne methods of DB, Tx and Stmt. Only the first column of the
+// result is scanned into dst.
+func LoadInto(dst interface{}) Loader {
+	return &intoLoader{dst: dst}
+}
+
+func (l *intoLoader) New() interface{} {
+	return l.dst
+}
+
+func (l *intoLoader) Load(colIdx int, _ string, b []byte) error {
+	if colIdx > 0 {
+		return nil
+	}
+	return Decode(l.dst, b)
+}
+
+// loaderFor adapts an arbitrary destination into a Loader. A
+// destination that already implements Loader is returned as-is;
+// anything else must be a pointer to a struct whose fields are matched
+// to columns by name.
+func loaderFor(dst interface{}) (Loader, error) {
+	if l, ok := dst.(Loader); ok {
+		return l, nil
+	}
+	return newStructLoader(dst)
+}
+
+type structLoader struct {
+	v reflect.Value
+}
+
+func newStructLoader(dst interface{}) (*structLoader, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("pg: Model(unsupported %T)", dst)
+	}
+	return &structLoader{v: v.Elem()}, nil
+}
+
+func (l *structLoader) New() interface{} {
+	return l.v.Addr().Interface()
+}
+
+func (l *structLoader) Load(_ int, colName string, b []byte) error {
+	f := l.v.FieldByNameFunc(func(name string) bool {
+		return strings.EqualFold(name, colName)
+	})
+	if !f.IsValid() {
+		return nil
+	}
+
+	if f.Kind() == reflect.Interface {
+		return Decode(f.Interface(), b)
+	}
+	return Decode(f.Addr().Interface(), b)
+}
+
+// Ints is a slice of int64 that (de)serializes as a PostgreSQL integer
+// array literal, e.g. {1,2,3}.
+type Ints []int64
+
+// Strings is a slice of string that (de)serializes as a PostgreSQL text
+// array literal, e.g. {"foo","bar"}.
+type Strings []string